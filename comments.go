@@ -0,0 +1,51 @@
+package reflector
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Comments holds the proto source comments associated with a single
+// declaration, following protoc's leading/trailing/detached
+// classification of comments around a SourceCodeInfo path.
+type Comments struct {
+	Leading         string
+	Trailing        string
+	LeadingDetached []string
+}
+
+// IsEmpty reports whether no comments were found for a declaration. It's
+// provided so callers (e.g. FormatServicesDetailed) can skip printing an
+// empty "// " prefix.
+func (c Comments) IsEmpty() bool {
+	return c.Leading == "" && c.Trailing == "" && len(c.LeadingDetached) == 0
+}
+
+// lookupComments finds fullName's declaration in sourceInfo and returns
+// its source comments. sourceInfo is expected to be a *protoregistry.Files
+// built from FileDescriptorProtos that retain source_code_info (e.g. via
+// protoc-gen-gosrcinfo); it is typically a different registry than the
+// one the descriptor being described came from, since generated code
+// normally strips source_code_info to save binary size. Returns the zero
+// Comments if sourceInfo is nil or the symbol isn't found there.
+func lookupComments(sourceInfo *protoregistry.Files, fullName protoreflect.FullName) Comments {
+	if sourceInfo == nil {
+		return Comments{}
+	}
+
+	d, err := sourceInfo.FindDescriptorByName(fullName)
+	if err != nil {
+		return Comments{}
+	}
+
+	loc := d.ParentFile().SourceLocations().ByDescriptor(d)
+	if loc.Path == nil {
+		return Comments{}
+	}
+
+	return Comments{
+		Leading:         loc.LeadingComments,
+		Trailing:        loc.TrailingComments,
+		LeadingDetached: loc.LeadingDetachedComments,
+	}
+}
@@ -0,0 +1,86 @@
+package reflector
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// buildCommentsTestSourceInfo builds a *protoregistry.Files holding a
+// single file whose lone message carries SourceCodeInfo, the way
+// protoc-gen-gosrcinfo's output would -- WithSourceInfo's only documented
+// input shape.
+func buildCommentsTestSourceInfo(t *testing.T) *protoregistry.Files {
+	t.Helper()
+
+	fdProto := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("reflector_comments_test.proto"),
+		Package: proto.String("commentspkg"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("Doc")},
+		},
+		SourceCodeInfo: &descriptorpb.SourceCodeInfo{
+			Location: []*descriptorpb.SourceCodeInfo_Location{
+				{
+					// message_type is field 4 of FileDescriptorProto;
+					// index 0 is this file's first (and only) message.
+					Path:                    []int32{4, 0},
+					Span:                    []int32{0, 0, 0, 10},
+					LeadingComments:         proto.String(" Doc is a test message.\n"),
+					TrailingComments:        proto.String(" trailing note.\n"),
+					LeadingDetachedComments: []string{" detached paragraph.\n"},
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdProto, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+
+	files := new(protoregistry.Files)
+	if err := files.RegisterFile(fd); err != nil {
+		t.Fatalf("RegisterFile: %v", err)
+	}
+	return files
+}
+
+func TestLookupCommentsFound(t *testing.T) {
+	sourceInfo := buildCommentsTestSourceInfo(t)
+
+	got := lookupComments(sourceInfo, protoreflect.FullName("commentspkg.Doc"))
+	if got.Leading != " Doc is a test message.\n" {
+		t.Errorf("Leading = %q, want %q", got.Leading, " Doc is a test message.\n")
+	}
+	if got.Trailing != " trailing note.\n" {
+		t.Errorf("Trailing = %q, want %q", got.Trailing, " trailing note.\n")
+	}
+	if len(got.LeadingDetached) != 1 || got.LeadingDetached[0] != " detached paragraph.\n" {
+		t.Errorf("LeadingDetached = %v, want [\" detached paragraph.\\n\"]", got.LeadingDetached)
+	}
+	if got.IsEmpty() {
+		t.Error("IsEmpty() = true for a populated Comments")
+	}
+}
+
+func TestLookupCommentsNilSourceInfo(t *testing.T) {
+	got := lookupComments(nil, protoreflect.FullName("commentspkg.Doc"))
+	if !got.IsEmpty() {
+		t.Errorf("lookupComments(nil, ...) = %+v, want zero value", got)
+	}
+}
+
+func TestLookupCommentsSymbolNotFound(t *testing.T) {
+	sourceInfo := buildCommentsTestSourceInfo(t)
+
+	got := lookupComments(sourceInfo, protoreflect.FullName("commentspkg.DoesNotExist"))
+	if !got.IsEmpty() {
+		t.Errorf("lookupComments for missing symbol = %+v, want zero value", got)
+	}
+}
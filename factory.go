@@ -0,0 +1,113 @@
+package reflector
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// MethodFactories constructs fresh, empty request/response messages for a
+// single gRPC method, via dynamicpb so no generated Go types are needed.
+type MethodFactories struct {
+	NewInput  func() proto.Message
+	NewOutput func() proto.Message
+}
+
+// GetMethodMessageFactories returns, for every method registered on
+// server, a MethodFactories keyed by full method name ("/pkg.Svc/Method").
+// This mirrors the Cosmos SDK's GRPCMethodsToMessageMap pattern: a caller
+// that only has a full method name and raw bytes (an HTTP gateway, a CLI,
+// a proxy) can build the correct concrete message, unmarshal into it, and
+// invoke the handler without any code generation.
+func GetMethodMessageFactories(server *grpc.Server, opts ...Option) (map[string]MethodFactories, error) {
+	options, err := resolveOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	factories := make(map[string]MethodFactories)
+
+	for serviceName, info := range server.GetServiceInfo() {
+		for _, method := range info.Methods {
+			fullMethodName := fmt.Sprintf("/%s/%s", serviceName, method.Name)
+			mf, err := methodFactories(fullMethodName, options.ProtoFiles)
+			if err != nil {
+				return nil, err
+			}
+			factories[fullMethodName] = mf
+		}
+	}
+
+	return factories, nil
+}
+
+// methodFactories resolves fullMethod to a descriptor and builds its
+// MethodFactories. Shared by GetMethodMessageFactories and DecodeRequest
+// so a caller doesn't have to pre-build the whole map just to decode one
+// request.
+func methodFactories(fullMethod string, extra *protoregistry.Files) (MethodFactories, error) {
+	desc, err := getMethodDescriptor(fullMethod, extra)
+	if err != nil {
+		return MethodFactories{}, fmt.Errorf("resolving descriptor for %s: %w", fullMethod, err)
+	}
+
+	inputDesc := desc.Input()
+	outputDesc := desc.Output()
+	return MethodFactories{
+		NewInput:  func() proto.Message { return dynamicpb.NewMessage(inputDesc) },
+		NewOutput: func() proto.Message { return dynamicpb.NewMessage(outputDesc) },
+	}, nil
+}
+
+// Codec decodes wire bytes into a proto.Message. It is implemented by
+// ProtoCodec and JSONCodec below, or by a caller's own type.
+type Codec interface {
+	Unmarshal(data []byte, m proto.Message) error
+}
+
+// ProtoCodec decodes the standard protobuf binary wire format.
+type ProtoCodec struct{}
+
+// Unmarshal implements Codec using proto.Unmarshal.
+func (ProtoCodec) Unmarshal(data []byte, m proto.Message) error {
+	return proto.Unmarshal(data, m)
+}
+
+// JSONCodec decodes protobuf's canonical JSON mapping via protojson.
+type JSONCodec struct {
+	// UnmarshalOptions is passed through to protojson.Unmarshal. The
+	// zero value rejects unknown fields, matching protojson's default.
+	UnmarshalOptions protojson.UnmarshalOptions
+}
+
+// Unmarshal implements Codec using protojson.Unmarshal.
+func (c JSONCodec) Unmarshal(data []byte, m proto.Message) error {
+	return c.UnmarshalOptions.Unmarshal(data, m)
+}
+
+// DecodeRequest builds the concrete input message for fullMethod and
+// decodes data into it with codec. It's the glue a downstream gateway or
+// CLI uses to turn raw request bytes into a proto.Message it can pass to
+// the gRPC handler, without needing to precompute a factory map.
+func DecodeRequest(fullMethod string, data []byte, codec Codec, opts ...Option) (proto.Message, error) {
+	options, err := resolveOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	mf, err := methodFactories(fullMethod, options.ProtoFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := mf.NewInput()
+	if err := codec.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("decoding request for %s: %w", fullMethod, err)
+	}
+
+	return msg, nil
+}
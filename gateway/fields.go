@@ -0,0 +1,115 @@
+package gateway
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// setFieldString resolves a dotted field path (e.g. "parent.name") against
+// msg, descending through message-typed fields, and sets the final
+// scalar field from its string representation. It's used for both path
+// variables and query parameters, which the google.api.http spec treats
+// the same way: a field selector plus a string value.
+func setFieldString(msg protoreflect.Message, fieldPath, value string) error {
+	field, owner, err := resolveField(msg, fieldPath)
+	if err != nil {
+		return err
+	}
+
+	v, err := parseScalar(field, value)
+	if err != nil {
+		return fmt.Errorf("field %q: %w", fieldPath, err)
+	}
+
+	if field.IsList() {
+		owner.Mutable(field).List().Append(v)
+	} else {
+		owner.Set(field, v)
+	}
+	return nil
+}
+
+// resolveField walks a dotted field path from msg's descriptor, creating
+// intermediate sub-messages as needed, and returns the leaf field
+// descriptor plus the message it belongs to.
+func resolveField(msg protoreflect.Message, fieldPath string) (protoreflect.FieldDescriptor, protoreflect.Message, error) {
+	parts := strings.Split(fieldPath, ".")
+	cur := msg
+	for i, name := range parts {
+		field := cur.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if field == nil {
+			return nil, nil, fmt.Errorf("unknown field %q on %s", name, cur.Descriptor().FullName())
+		}
+		if i == len(parts)-1 {
+			return field, cur, nil
+		}
+		if field.Kind() != protoreflect.MessageKind {
+			return nil, nil, fmt.Errorf("field %q is not a message, cannot descend into %q", name, fieldPath)
+		}
+		cur = cur.Mutable(field).Message()
+	}
+	return nil, nil, fmt.Errorf("empty field path")
+}
+
+// parseScalar converts a string into the protoreflect.Value matching
+// field's kind. Message/group/map fields aren't valid targets for path
+// vars or query params and return an error.
+func parseScalar(field protoreflect.FieldDescriptor, value string) (protoreflect.Value, error) {
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(value), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfBool(b), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfInt32(int32(n)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfInt64(n), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfUint32(uint32(n)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfUint64(n), nil
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfFloat32(float32(f)), nil
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfFloat64(f), nil
+	case protoreflect.EnumKind:
+		v := field.Enum().Values().ByName(protoreflect.Name(value))
+		if v == nil {
+			return protoreflect.Value{}, fmt.Errorf("unknown enum value %q", value)
+		}
+		return protoreflect.ValueOfEnum(v.Number()), nil
+	default:
+		return protoreflect.Value{}, fmt.Errorf("unsupported kind %s for string value", field.Kind())
+	}
+}
@@ -0,0 +1,94 @@
+// Package gateway generates an HTTP/JSON transcoding front end for a
+// *grpc.Server from the google.api.http annotations on its methods, the
+// same annotations grpc-gateway's protoc plugin consumes at codegen
+// time -- except here the routes are derived at runtime from reflected
+// descriptors, so no generated gateway code is required.
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"google.golang.org/grpc"
+
+	reflector "github.com/yhonda-ohishi-pub-dev/grpc-service-reflector"
+)
+
+// Options configures a Gateway.
+type Options struct {
+	// Conn is used to invoke the underlying gRPC methods once a REST
+	// request has been transcoded. It's typically a loopback
+	// *grpc.ClientConn dialed back to the same address the *grpc.Server
+	// passed to New is serving on.
+	Conn *grpc.ClientConn
+
+	// ErrorHandler, if set, controls how transcoding and invocation
+	// errors are written to the HTTP response. The default writes a
+	// plain-text 400/500 body depending on the error.
+	ErrorHandler func(ctx context.Context, err error) (status int, body []byte)
+}
+
+// Gateway routes incoming REST calls to gRPC methods via the bindings
+// declared in their google.api.http options.
+type Gateway struct {
+	routes []*route
+	opts   Options
+}
+
+// New inspects every method server has registered, compiles any
+// google.api.http bindings found on its descriptor, and returns a
+// Gateway ready to produce an http.Handler. Methods without an http
+// annotation are skipped; New does not error because a service has no
+// annotated methods. New does return an error if any method registered
+// on server has no descriptor that reflector can resolve.
+func New(server *grpc.Server, opts Options) (*Gateway, error) {
+	if opts.Conn == nil {
+		return nil, fmt.Errorf("gateway: Options.Conn is required to invoke transcoded requests")
+	}
+
+	factories, err := reflector.GetMethodMessageFactories(server)
+	if err != nil {
+		return nil, fmt.Errorf("gateway: building method factories: %w", err)
+	}
+
+	gw := &Gateway{opts: opts}
+
+	serviceInfo := server.GetServiceInfo()
+	serviceNames := make([]string, 0, len(serviceInfo))
+	for serviceName := range serviceInfo {
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+
+	for _, serviceName := range serviceNames {
+		methods := append([]grpc.MethodInfo(nil), serviceInfo[serviceName].Methods...)
+		sort.Slice(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+
+		for _, method := range methods {
+			fullMethod := fmt.Sprintf("/%s/%s", serviceName, method.Name)
+			desc, err := reflector.MethodDescriptor(fullMethod)
+			if err != nil {
+				// GetMethodMessageFactories above has already
+				// resolved a descriptor for every registered method
+				// (New returns its error otherwise), so this lookup
+				// is not expected to fail; if it does, the method is
+				// skipped.
+				continue
+			}
+
+			rules := httpRulesForMethod(desc)
+			for _, rule := range rules {
+				r, err := compileRoute(fullMethod, desc, rule, factories[fullMethod])
+				if err != nil {
+					return nil, fmt.Errorf("gateway: compiling route for %s: %w", fullMethod, err)
+				}
+				gw.routes = append(gw.routes, r)
+			}
+		}
+	}
+
+	sortRoutes(gw.routes)
+
+	return gw, nil
+}
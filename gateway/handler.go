@@ -0,0 +1,143 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// httpError pairs an error with the HTTP status it should produce, so
+// the default error writer doesn't have to guess.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e *httpError) Error() string { return e.err.Error() }
+func (e *httpError) Unwrap() error { return e.err }
+
+func errBodyNotMessage(field string) error {
+	return fmt.Errorf("body selector %q does not refer to a message field", field)
+}
+
+// Handler returns an http.Handler that transcodes REST requests matching
+// any compiled google.api.http binding into gRPC calls over gw's
+// connection.
+func (gw *Gateway) Handler() http.Handler {
+	return gw
+}
+
+func (gw *Gateway) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	for _, r := range gw.routes {
+		vars, ok := r.match(req.Method, req.URL.Path)
+		if !ok {
+			continue
+		}
+
+		respBody, err := gw.invoke(ctx, r, vars, req)
+		if err != nil {
+			gw.writeError(w, ctx, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(respBody)
+		return
+	}
+
+	http.NotFound(w, req)
+}
+
+func (gw *Gateway) invoke(ctx context.Context, r *route, pathVars map[string]string, req *http.Request) ([]byte, error) {
+	in := r.factories.NewInput()
+	inMsg := in.ProtoReflect()
+
+	for name, value := range pathVars {
+		if err := setFieldString(inMsg, name, value); err != nil {
+			return nil, &httpError{http.StatusBadRequest, err}
+		}
+	}
+
+	if r.body != "" {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, &httpError{http.StatusBadRequest, err}
+		}
+
+		target := in
+		if r.body != "*" {
+			field, owner, err := resolveField(inMsg, r.body)
+			if err != nil {
+				return nil, &httpError{http.StatusBadRequest, err}
+			}
+			if field.Kind() != protoreflect.MessageKind {
+				return nil, &httpError{http.StatusBadRequest, errBodyNotMessage(r.body)}
+			}
+			target = owner.Mutable(field).Message().Interface()
+		}
+		if len(data) > 0 {
+			if err := protojson.Unmarshal(data, target); err != nil {
+				return nil, &httpError{http.StatusBadRequest, err}
+			}
+		}
+	} else {
+		if err := populateFromQuery(inMsg, req.URL.Query()); err != nil {
+			return nil, &httpError{http.StatusBadRequest, err}
+		}
+	}
+
+	out := r.factories.NewOutput()
+	if err := gw.opts.Conn.Invoke(ctx, r.fullMethod, in, out); err != nil {
+		return nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	var respMsg proto.Message = out
+	if r.responseBodyField != "" {
+		field, owner, err := resolveField(out.ProtoReflect(), r.responseBodyField)
+		if err != nil {
+			return nil, &httpError{http.StatusInternalServerError, err}
+		}
+		respMsg = owner.Get(field).Message().Interface()
+	}
+
+	data, err := protojson.Marshal(respMsg)
+	if err != nil {
+		return nil, &httpError{http.StatusInternalServerError, err}
+	}
+	return data, nil
+}
+
+// populateFromQuery sets fields named (or dotted-path named) by query
+// parameters, mirroring grpc-gateway's handling of unbound fields.
+func populateFromQuery(msg protoreflect.Message, query map[string][]string) error {
+	for key, values := range query {
+		for _, v := range values {
+			if err := setFieldString(msg, key, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (gw *Gateway) writeError(w http.ResponseWriter, ctx context.Context, err error) {
+	if gw.opts.ErrorHandler != nil {
+		status, body := gw.opts.ErrorHandler(ctx, err)
+		w.WriteHeader(status)
+		w.Write(body)
+		return
+	}
+
+	status := http.StatusInternalServerError
+	if he, ok := err.(*httpError); ok {
+		status = he.status
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,62 @@
+package gateway
+
+import (
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// httpRule is our own flattened view of a google.api.HttpRule binding:
+// one HTTP method/path/body combination. A single proto method can
+// declare several via additional_bindings.
+type httpRule struct {
+	method       string // GET, POST, PUT, PATCH, DELETE
+	path         string // raw path template, e.g. "/v1/{name=shelves/*}/books"
+	body         string // "", "*", or a field selector
+	responseBody string
+}
+
+// httpRulesForMethod extracts the google.api.http option (primary plus
+// additional_bindings) from a method descriptor, returning nil if the
+// method has none.
+func httpRulesForMethod(desc protoreflect.MethodDescriptor) []httpRule {
+	opts := desc.Options()
+	if opts == nil || !proto.HasExtension(opts, annotations.E_Http) {
+		return nil
+	}
+
+	rule, ok := proto.GetExtension(opts, annotations.E_Http).(*annotations.HttpRule)
+	if !ok || rule == nil {
+		return nil
+	}
+
+	rules := []httpRule{flattenRule(rule)}
+	for _, additional := range rule.GetAdditionalBindings() {
+		rules = append(rules, flattenRule(additional))
+	}
+	return rules
+}
+
+func flattenRule(rule *annotations.HttpRule) httpRule {
+	r := httpRule{
+		body:         rule.GetBody(),
+		responseBody: rule.GetResponseBody(),
+	}
+
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		r.method, r.path = "GET", pattern.Get
+	case *annotations.HttpRule_Put:
+		r.method, r.path = "PUT", pattern.Put
+	case *annotations.HttpRule_Post:
+		r.method, r.path = "POST", pattern.Post
+	case *annotations.HttpRule_Delete:
+		r.method, r.path = "DELETE", pattern.Delete
+	case *annotations.HttpRule_Patch:
+		r.method, r.path = "PATCH", pattern.Patch
+	case *annotations.HttpRule_Custom:
+		r.method, r.path = pattern.Custom.GetKind(), pattern.Custom.GetPath()
+	}
+
+	return r
+}
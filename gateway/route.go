@@ -0,0 +1,142 @@
+package gateway
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	reflector "github.com/yhonda-ohishi-pub-dev/grpc-service-reflector"
+)
+
+// route is a compiled httpRule ready to match incoming requests.
+type route struct {
+	fullMethod string
+	httpMethod string
+	body       string
+	inputDesc  protoreflect.MessageDescriptor
+	outputDesc protoreflect.MessageDescriptor
+	factories  reflector.MethodFactories
+
+	path     string // the original google.api.http path template, kept for deterministic ordering
+	pattern  *regexp.Regexp
+	pathVars []string // capture group index i corresponds to pathVars[i]
+	greedy   bool     // true if the template ends in a {var=**} catch-all segment
+
+	responseBodyField string
+}
+
+// compileRoute turns a proto method descriptor plus one of its
+// google.api.http bindings into a matchable route.
+func compileRoute(fullMethod string, desc protoreflect.MethodDescriptor, rule httpRule, factories reflector.MethodFactories) (*route, error) {
+	pattern, vars, greedy, err := compilePathTemplate(rule.path)
+	if err != nil {
+		return nil, fmt.Errorf("path template %q: %w", rule.path, err)
+	}
+
+	return &route{
+		fullMethod:        fullMethod,
+		httpMethod:        rule.method,
+		body:              rule.body,
+		inputDesc:         desc.Input(),
+		outputDesc:        desc.Output(),
+		factories:         factories,
+		path:              rule.path,
+		pattern:           pattern,
+		pathVars:          vars,
+		greedy:            greedy,
+		responseBodyField: rule.responseBody,
+	}, nil
+}
+
+// compilePathTemplate compiles a google.api.http path template into a
+// regexp and the ordered list of path variable names its capture groups
+// correspond to. It supports the two segment forms transcoding specs
+// actually use in practice:
+//
+//	{var}      - captures exactly one path segment
+//	{var=**}   - captures the remainder of the path, slashes included
+//
+// A bare field path with dots (e.g. {nested.field}) is preserved as the
+// variable name; setFieldString is responsible for resolving it against
+// the message.
+func compilePathTemplate(template string) (*regexp.Regexp, []string, bool, error) {
+	var sb strings.Builder
+	sb.WriteString("^/")
+
+	var vars []string
+	var sawGreedy bool
+	segments := strings.Split(strings.Trim(template, "/"), "/")
+	for i, seg := range segments {
+		if i > 0 {
+			sb.WriteByte('/')
+		}
+
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			inner := seg[1 : len(seg)-1]
+			name := inner
+			greedy := false
+			if idx := strings.Index(inner, "="); idx >= 0 {
+				name = inner[:idx]
+				greedy = inner[idx+1:] == "**"
+			}
+			vars = append(vars, name)
+			if greedy {
+				sawGreedy = true
+				sb.WriteString("(.+)")
+			} else {
+				sb.WriteString("([^/]+)")
+			}
+			continue
+		}
+
+		sb.WriteString(regexp.QuoteMeta(seg))
+	}
+	sb.WriteByte('$')
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return re, vars, sawGreedy, nil
+}
+
+// sortRoutes orders routes so a request path matching more than one of
+// them always resolves the same winner regardless of the order they were
+// compiled in (gateway.New compiles them in service-name, method-name
+// order, which says nothing about how specific a template is): non-greedy
+// (fixed-segment) templates
+// take precedence over {var=**} catch-alls, since a catch-all would
+// otherwise swallow requests meant for a more specific route, and any
+// remaining tie is broken by the template text itself so the result is
+// stable across runs.
+func sortRoutes(routes []*route) {
+	sort.SliceStable(routes, func(i, j int) bool {
+		a, b := routes[i], routes[j]
+		if a.greedy != b.greedy {
+			return !a.greedy
+		}
+		return a.path < b.path
+	})
+}
+
+// match reports whether method+path satisfy this route, returning the
+// path variable values keyed by name if so.
+func (r *route) match(method, path string) (map[string]string, bool) {
+	if method != r.httpMethod {
+		return nil, false
+	}
+
+	m := r.pattern.FindStringSubmatch(path)
+	if m == nil {
+		return nil, false
+	}
+
+	vars := make(map[string]string, len(r.pathVars))
+	for i, name := range r.pathVars {
+		vars[name] = m[i+1]
+	}
+	return vars, true
+}
@@ -0,0 +1,72 @@
+package gateway
+
+import "testing"
+
+// newTestRoute builds a route from a path template only, bypassing
+// compileRoute's descriptor/factory plumbing -- match and sortRoutes only
+// look at the fields populated here.
+func newTestRoute(t *testing.T, method, path string) *route {
+	t.Helper()
+	pattern, vars, greedy, err := compilePathTemplate(path)
+	if err != nil {
+		t.Fatalf("compilePathTemplate(%q): %v", path, err)
+	}
+	return &route{
+		httpMethod: method,
+		path:       path,
+		pattern:    pattern,
+		pathVars:   vars,
+		greedy:     greedy,
+	}
+}
+
+// TestSortRoutesPrefersSpecificOverGreedy covers the ambiguous-overlap
+// case two google.api.http bindings can declare on the same service:
+// "/v1/items/{id}" and "/v1/items/{path=**}" both match a request for
+// "/v1/items/42". Without sortRoutes, which one wins depends on the order
+// gateway.New compiled them in, i.e. on service and method names.
+func TestSortRoutesPrefersSpecificOverGreedy(t *testing.T) {
+	specific := newTestRoute(t, "GET", "/v1/items/{id}")
+	greedy := newTestRoute(t, "GET", "/v1/items/{path=**}")
+
+	for _, routes := range [][]*route{
+		{greedy, specific},
+		{specific, greedy},
+	} {
+		sortRoutes(routes)
+		if routes[0] != specific {
+			t.Fatalf("expected non-greedy route %q to sort before greedy route %q", specific.path, greedy.path)
+		}
+
+		vars, ok := routes[0].match("GET", "/v1/items/42")
+		if !ok {
+			t.Fatalf("expected %q to match /v1/items/42", specific.path)
+		}
+		if vars["id"] != "42" {
+			t.Fatalf("id = %q, want 42", vars["id"])
+		}
+	}
+}
+
+// TestSortRoutesStableOnTie checks that two routes of equal greediness
+// sort by template text, independent of their starting order, so repeated
+// gateway.New calls over the same service set produce the same order.
+func TestSortRoutesStableOnTie(t *testing.T) {
+	a := newTestRoute(t, "GET", "/v1/a/{id}")
+	b := newTestRoute(t, "GET", "/v1/b/{id}")
+
+	routes := []*route{b, a}
+	sortRoutes(routes)
+	if routes[0] != a || routes[1] != b {
+		t.Fatalf("expected stable order [a, b], got [%s, %s]", routes[0].path, routes[1].path)
+	}
+}
+
+func TestCompilePathTemplateGreedyFlag(t *testing.T) {
+	if _, _, greedy, err := compilePathTemplate("/v1/items/{id}"); err != nil || greedy {
+		t.Fatalf("greedy = %v, err = %v; want false, nil", greedy, err)
+	}
+	if _, _, greedy, err := compilePathTemplate("/v1/items/{path=**}"); err != nil || !greedy {
+		t.Fatalf("greedy = %v, err = %v; want true, nil", greedy, err)
+	}
+}
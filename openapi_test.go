@@ -0,0 +1,195 @@
+package reflector
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestScalarJSONSchema(t *testing.T) {
+	cases := map[string]map[string]interface{}{
+		"bool":   {"type": "boolean"},
+		"int64":  {"type": "string", "format": "int64"},
+		"uint32": {"type": "integer", "format": "uint32"},
+		"double": {"type": "number", "format": "double"},
+		"string": {"type": "string"},
+	}
+	for kind, want := range cases {
+		got := scalarJSONSchema(kind)
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		if string(gotJSON) != string(wantJSON) {
+			t.Errorf("scalarJSONSchema(%q) = %s, want %s", kind, gotJSON, wantJSON)
+		}
+	}
+}
+
+// TestFieldJSONSchemaWellKnownTypes guards the Timestamp/Duration special
+// case ahead of the generic message branch: both must render as the
+// strings protojson actually produces on the wire, not as a $ref to their
+// {seconds, nanos} wire struct.
+func TestFieldJSONSchemaWellKnownTypes(t *testing.T) {
+	components := map[string]interface{}{}
+
+	ts := FieldInfo{Name: "created_at", Message: &MessageSchema{Name: "google.protobuf.Timestamp"}}
+	got := fieldJSONSchema(ts, components)
+	if got["type"] != "string" || got["format"] != "date-time" {
+		t.Errorf("Timestamp field schema = %v, want {type: string, format: date-time}", got)
+	}
+
+	dur := FieldInfo{Name: "timeout", Message: &MessageSchema{Name: "google.protobuf.Duration"}}
+	got = fieldJSONSchema(dur, components)
+	if got["type"] != "string" || got["format"] != "duration" {
+		t.Errorf("Duration field schema = %v, want {type: string, format: duration}", got)
+	}
+
+	if len(components) != 0 {
+		t.Errorf("well-known type fields registered %d components, want 0 (no $ref expected)", len(components))
+	}
+}
+
+func TestFieldJSONSchemaMessageRef(t *testing.T) {
+	components := map[string]interface{}{}
+	field := FieldInfo{
+		Name: "author",
+		Message: &MessageSchema{
+			Name:   "pkg.Author",
+			Fields: []FieldInfo{{Name: "name", Type: "string"}},
+		},
+	}
+
+	got := fieldJSONSchema(field, components)
+	if got["$ref"] != "#/components/schemas/pkg.Author" {
+		t.Errorf("message field schema = %v, want $ref to pkg.Author", got)
+	}
+	if _, ok := components["pkg.Author"]; !ok {
+		t.Error("schemaRef did not register pkg.Author in components")
+	}
+}
+
+func TestFieldJSONSchemaMap(t *testing.T) {
+	field := FieldInfo{
+		Name: "tags",
+		Map: &MapEntry{
+			Key:   FieldInfo{Name: "key", Type: "string"},
+			Value: FieldInfo{Name: "value", Type: "string"},
+		},
+	}
+
+	got := fieldJSONSchema(field, map[string]interface{}{})
+	if got["type"] != "object" {
+		t.Fatalf("map field schema type = %v, want object", got["type"])
+	}
+	additional, ok := got["additionalProperties"].(map[string]interface{})
+	if !ok || additional["type"] != "string" {
+		t.Errorf("map field additionalProperties = %v, want {type: string}", got["additionalProperties"])
+	}
+}
+
+func TestFieldJSONSchemaRepeated(t *testing.T) {
+	field := FieldInfo{Name: "names", Type: "string", Repeated: true}
+	got := fieldJSONSchema(field, map[string]interface{}{})
+	if got["type"] != "array" {
+		t.Fatalf("repeated field schema type = %v, want array", got["type"])
+	}
+	items, ok := got["items"].(map[string]interface{})
+	if !ok || items["type"] != "string" {
+		t.Errorf("repeated field items = %v, want {type: string}", got["items"])
+	}
+}
+
+// TestMessageJSONSchemaOneof ensures a oneof is surfaced as a synthetic
+// "_<name>" property listing its alternatives, alongside its member
+// fields as ordinary properties.
+func TestMessageJSONSchemaOneof(t *testing.T) {
+	schema := &MessageSchema{
+		Name: "pkg.Shape",
+		Fields: []FieldInfo{
+			{Name: "circle", Type: "string"},
+			{Name: "square", Type: "string"},
+		},
+		Oneofs: []OneofInfo{
+			{Name: "kind", Fields: []string{"circle", "square"}},
+		},
+	}
+
+	got := messageJSONSchema(schema, map[string]interface{}{})
+	properties, ok := got["properties"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("properties missing or wrong type: %v", got["properties"])
+	}
+	if _, ok := properties["circle"]; !ok {
+		t.Error("properties missing oneof member \"circle\"")
+	}
+	oneofProp, ok := properties["_kind"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("properties missing synthetic oneof key \"_kind\": %v", properties)
+	}
+	alternatives, ok := oneofProp["oneOf"].([]interface{})
+	if !ok || len(alternatives) != 2 {
+		t.Errorf("_kind.oneOf = %v, want 2 alternatives", oneofProp["oneOf"])
+	}
+}
+
+// TestToOpenAPI exercises the full document assembly for a service whose
+// method reflector.MethodDescriptor can't resolve (no descriptor is
+// registered in this test), so it must fall back to the default
+// Connect-style POST binding rather than erroring.
+func TestToOpenAPI(t *testing.T) {
+	services := []ServiceInfo{
+		{
+			Name: "pkg.Greeter",
+			Methods: []MethodInfo{
+				{
+					Name:       "SayHello",
+					InputType:  "pkg.HelloRequest",
+					OutputType: "pkg.HelloResponse",
+					InputSchema: &MessageSchema{
+						Name:   "pkg.HelloRequest",
+						Fields: []FieldInfo{{Name: "name", Type: "string"}},
+					},
+					OutputSchema: &MessageSchema{
+						Name:   "pkg.HelloResponse",
+						Fields: []FieldInfo{{Name: "greeting", Type: "string"}},
+					},
+				},
+			},
+		},
+	}
+
+	doc, err := ToOpenAPI(services, OpenAPIOptions{Title: "Test API", Version: "1.2.3"})
+	if err != nil {
+		t.Fatalf("ToOpenAPI: %v", err)
+	}
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(doc, &parsed); err != nil {
+		t.Fatalf("ToOpenAPI output is not valid JSON: %v", err)
+	}
+
+	paths, ok := parsed["paths"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("paths missing or wrong type: %v", parsed["paths"])
+	}
+	pathItem, ok := paths["/pkg.Greeter/SayHello"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("missing default-bound path /pkg.Greeter/SayHello: %v", paths)
+	}
+	if _, ok := pathItem["post"]; !ok {
+		t.Errorf("pathItem = %v, want a \"post\" operation (default binding)", pathItem)
+	}
+
+	components, ok := parsed["components"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("components missing or wrong type: %v", parsed["components"])
+	}
+	schemas, ok := components["schemas"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("components.schemas missing or wrong type: %v", components)
+	}
+	if _, ok := schemas["pkg.HelloRequest"]; !ok {
+		t.Errorf("schemas missing pkg.HelloRequest: %v", schemas)
+	}
+	if _, ok := schemas["pkg.HelloResponse"]; !ok {
+		t.Errorf("schemas missing pkg.HelloResponse: %v", schemas)
+	}
+}
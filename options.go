@@ -0,0 +1,61 @@
+package reflector
+
+import "google.golang.org/protobuf/reflect/protoregistry"
+
+// Options controls how deeply and from which descriptor sources reflector
+// walks proto schemas.
+type Options struct {
+	// MaxDepth caps how many levels of nested message fields are
+	// expanded before recursion stops and a leaf MessageSchema (name
+	// only, no fields) is returned. Zero means unlimited.
+	MaxDepth int
+
+	// SourceInfo, if set, is consulted for proto source comments
+	// (leading/trailing/detached) attached to services, methods,
+	// messages and fields. See WithSourceInfo.
+	SourceInfo *protoregistry.Files
+
+	// ProtoFiles, if set, is consulted alongside protoregistry.GlobalFiles
+	// when resolving method and message descriptors, so services whose
+	// generated Go stubs were never linked into the binary can still be
+	// reflected on. See WithProtoSources.
+	ProtoFiles *protoregistry.Files
+
+	err error
+}
+
+// Option configures an Options value.
+type Option func(*Options)
+
+// WithMaxDepth caps recursive message schema traversal at depth levels of
+// nesting. This guards against pathological or self-referential protos
+// producing unbounded output.
+func WithMaxDepth(depth int) Option {
+	return func(o *Options) {
+		o.MaxDepth = depth
+	}
+}
+
+// WithSourceInfo supplies a *protoregistry.Files whose FileDescriptorProtos
+// retain source_code_info -- typically produced by protoc-gen-gosrcinfo,
+// since protoc-gen-go strips it from the descriptors it embeds by
+// default. GetServices and getMessageSchema use globals to look
+// up the leading/trailing comments for each symbol they describe,
+// following the same approach as jhump/protoreflect's sourceinfo
+// package.
+func WithSourceInfo(globals *protoregistry.Files) Option {
+	return func(o *Options) {
+		o.SourceInfo = globals
+	}
+}
+
+func resolveOptions(opts []Option) (Options, error) {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.err != nil {
+		return Options{}, o.err
+	}
+	return o, nil
+}
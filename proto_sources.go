@@ -0,0 +1,92 @@
+package reflector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// WithProtoSources parses and links the given .proto files (resolved
+// against importPaths) with protocompile and makes their descriptors
+// available to getMethodDescriptor -- and, once passed to
+// RegisterReflectionServer, to the reflection service itself -- in
+// addition to whatever protoregistry.GlobalFiles already holds. This is
+// the same transition evans made away from relying solely on the old
+// proto.FileDescriptor registry: it lets reflector describe services
+// whose generated Go stubs were never linked into the binary, because
+// grpc.Server.RegisterService was called directly against a
+// grpc.ServiceDesc built some other way.
+//
+// The sources are parsed and linked once, here, rather than inside the
+// returned Option: an Option runs on every resolveOptions call, and
+// callers like GetMethodMessageFactories and DecodeRequest are expected
+// to pass the same WithProtoSources(...) on every request, so compiling
+// inside the closure would re-parse and re-link the same files on every
+// single call.
+func WithProtoSources(paths []string, importPaths []string) Option {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: importPaths,
+		}),
+	}
+
+	compiled, err := compiler.Compile(context.Background(), paths...)
+	if err != nil {
+		err = fmt.Errorf("compiling proto sources: %w", err)
+		return func(o *Options) { o.err = err }
+	}
+
+	files := new(protoregistry.Files)
+	for _, fd := range compiled {
+		if regErr := files.RegisterFile(fd); regErr != nil {
+			err = fmt.Errorf("registering compiled proto file %s: %w", fd.Path(), regErr)
+			return func(o *Options) { o.err = err }
+		}
+	}
+
+	return func(o *Options) {
+		o.ProtoFiles = files
+	}
+}
+
+// findFileByPath looks up path in extra (if non-nil) before falling back
+// to protoregistry.GlobalFiles.
+func findFileByPath(extra *protoregistry.Files, path string) (protoreflect.FileDescriptor, error) {
+	if extra != nil {
+		if fd, err := extra.FindFileByPath(path); err == nil {
+			return fd, nil
+		}
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+// findDescriptorByName looks up name in extra (if non-nil) before
+// falling back to protoregistry.GlobalFiles.
+func findDescriptorByName(extra *protoregistry.Files, name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if extra != nil {
+		if d, err := extra.FindDescriptorByName(name); err == nil {
+			return d, nil
+		}
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
+
+// rangeFiles ranges extra's files (if non-nil) followed by
+// protoregistry.GlobalFiles', stopping as soon as fn returns false in
+// either set.
+func rangeFiles(extra *protoregistry.Files, fn func(protoreflect.FileDescriptor) bool) {
+	cont := true
+	if extra != nil {
+		extra.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+			cont = fn(fd)
+			return cont
+		})
+	}
+	if !cont {
+		return
+	}
+	protoregistry.GlobalFiles.RangeFiles(fn)
+}
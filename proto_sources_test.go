@@ -0,0 +1,196 @@
+package reflector
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestWithProtoSourcesCompilesAndRegisters(t *testing.T) {
+	dir := t.TempDir()
+	src := `syntax = "proto3";
+package protosourcespkg;
+
+service Echo {
+  rpc Ping(PingRequest) returns (PingResponse);
+}
+
+message PingRequest {}
+message PingResponse {}
+`
+	if err := os.WriteFile(filepath.Join(dir, "echo.proto"), []byte(src), 0o644); err != nil {
+		t.Fatalf("writing test .proto: %v", err)
+	}
+
+	opt := WithProtoSources([]string{"echo.proto"}, []string{dir})
+
+	options, err := resolveOptions([]Option{opt})
+	if err != nil {
+		t.Fatalf("resolveOptions: %v", err)
+	}
+	if options.ProtoFiles == nil {
+		t.Fatal("Options.ProtoFiles is nil after WithProtoSources")
+	}
+
+	fd, err := options.ProtoFiles.FindFileByPath("echo.proto")
+	if err != nil {
+		t.Fatalf("FindFileByPath(echo.proto): %v", err)
+	}
+	if string(fd.Package()) != "protosourcespkg" {
+		t.Errorf("fd.Package() = %q, want protosourcespkg", fd.Package())
+	}
+	if fd.Services().ByName("Echo") == nil {
+		t.Error("compiled file is missing service Echo")
+	}
+}
+
+// TestWithProtoSourcesCompilesOnce guards against the bug where
+// compilation happened inside the returned Option closure and so reran
+// on every resolveOptions call: the same WithProtoSources(...) value,
+// passed to resolveOptions twice, must hand back the identical
+// *protoregistry.Files both times rather than a freshly recompiled one.
+func TestWithProtoSourcesCompilesOnce(t *testing.T) {
+	dir := t.TempDir()
+	src := `syntax = "proto3";
+package protosourcespkg;
+
+message Empty {}
+`
+	if err := os.WriteFile(filepath.Join(dir, "empty.proto"), []byte(src), 0o644); err != nil {
+		t.Fatalf("writing test .proto: %v", err)
+	}
+
+	opt := WithProtoSources([]string{"empty.proto"}, []string{dir})
+
+	first, err := resolveOptions([]Option{opt})
+	if err != nil {
+		t.Fatalf("resolveOptions (first call): %v", err)
+	}
+	second, err := resolveOptions([]Option{opt})
+	if err != nil {
+		t.Fatalf("resolveOptions (second call): %v", err)
+	}
+
+	if first.ProtoFiles != second.ProtoFiles {
+		t.Error("WithProtoSources recompiled on a second resolveOptions call instead of reusing the first compile")
+	}
+}
+
+// registerShadowGlobalOnce registers shadow_global_test.proto into
+// protoregistry.GlobalFiles the first time it's called; every test
+// function in this file needs that registration present but
+// RegisterFile panics on a second, duplicate call, so the actual
+// registration is guarded to run exactly once across the test binary.
+var registerShadowGlobalOnce sync.Once
+
+// buildShadowTestRegistries returns a protoregistry.Files ("extra") and
+// ensures a distinct descriptor of the same full name is registered into
+// protoregistry.GlobalFiles, so findFileByPath/findDescriptorByName's
+// "extra first, then GlobalFiles" merge-then-shadow behavior can be
+// observed directly: a lookup must prefer extra's version.
+func buildShadowTestRegistries(t *testing.T) (extra *protoregistry.Files, extraPath, globalPath string) {
+	t.Helper()
+
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	tString := descriptorpb.FieldDescriptorProto_TYPE_STRING
+
+	registerShadowGlobalOnce.Do(func() {
+		globalFD := &descriptorpb.FileDescriptorProto{
+			Name:    proto.String("shadow_global_test.proto"),
+			Package: proto.String("shadowpkg"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				{
+					Name: proto.String("Thing"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{Name: proto.String("global_marker"), Number: proto.Int32(1), Label: &optional, Type: &tString},
+					},
+				},
+			},
+		}
+		globalDesc, err := protodesc.NewFile(globalFD, protoregistry.GlobalFiles)
+		if err != nil {
+			t.Fatalf("protodesc.NewFile (global): %v", err)
+		}
+		if err := protoregistry.GlobalFiles.RegisterFile(globalDesc); err != nil {
+			t.Fatalf("RegisterFile (global): %v", err)
+		}
+	})
+
+	extraFD := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("shadow_extra_test.proto"),
+		Package: proto.String("shadowpkg"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Thing"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("extra_marker"), Number: proto.Int32(1), Label: &optional, Type: &tString},
+				},
+			},
+		},
+	}
+	extraDesc, err := protodesc.NewFile(extraFD, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile (extra): %v", err)
+	}
+
+	extra = new(protoregistry.Files)
+	if err := extra.RegisterFile(extraDesc); err != nil {
+		t.Fatalf("RegisterFile (extra): %v", err)
+	}
+
+	return extra, "shadow_extra_test.proto", "shadow_global_test.proto"
+}
+
+func TestFindDescriptorByNamePrefersExtra(t *testing.T) {
+	extra, _, _ := buildShadowTestRegistries(t)
+
+	d, err := findDescriptorByName(extra, protoreflect.FullName("shadowpkg.Thing"))
+	if err != nil {
+		t.Fatalf("findDescriptorByName: %v", err)
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		t.Fatalf("findDescriptorByName returned %T, want MessageDescriptor", d)
+	}
+	if md.Fields().ByName("extra_marker") == nil {
+		t.Error("findDescriptorByName returned the GlobalFiles copy of shadowpkg.Thing instead of extra's shadowing copy")
+	}
+}
+
+func TestFindFileByPathFallsBackToGlobal(t *testing.T) {
+	extra, extraPath, globalPath := buildShadowTestRegistries(t)
+
+	if _, err := findFileByPath(extra, extraPath); err != nil {
+		t.Errorf("findFileByPath(extra, %q): %v", extraPath, err)
+	}
+	// globalPath isn't in extra, so the lookup must fall through to
+	// protoregistry.GlobalFiles rather than failing.
+	if _, err := findFileByPath(extra, globalPath); err != nil {
+		t.Errorf("findFileByPath(extra, %q) did not fall back to GlobalFiles: %v", globalPath, err)
+	}
+	if _, err := findFileByPath(nil, globalPath); err != nil {
+		t.Errorf("findFileByPath(nil, %q): %v", globalPath, err)
+	}
+}
+
+func TestRangeFilesStopsWithoutConsultingGlobal(t *testing.T) {
+	extra, _, _ := buildShadowTestRegistries(t)
+
+	visited := 0
+	rangeFiles(extra, func(protoreflect.FileDescriptor) bool {
+		visited++
+		return false
+	})
+	if visited != 1 {
+		t.Errorf("rangeFiles visited %d files before an fn returning false stopped it, want 1 (extra's only file)", visited)
+	}
+}
@@ -0,0 +1,302 @@
+package reflector
+
+import (
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// RegisterReflectionServer registers both the v1 and v1alpha gRPC Server
+// Reflection services on s, backed by the same descriptor lookup used by
+// GetServices. Unlike google.golang.org/grpc/reflection, the descriptor
+// set served here isn't limited to protoregistry.GlobalFiles: passing
+// WithProtoSources lets the reflection service also describe services
+// whose generated Go stubs were never linked into the binary.
+func RegisterReflectionServer(s *grpc.Server, opts ...Option) error {
+	options, err := resolveOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	impl := &reflectionServer{services: s, extraFiles: options.ProtoFiles}
+	grpc_reflection_v1.RegisterServerReflectionServer(s, impl)
+	grpc_reflection_v1alpha.RegisterServerReflectionServer(s, v1alphaReflectionServer{impl})
+	return nil
+}
+
+// reflectionServer implements grpc_reflection_v1.ServerReflectionServer.
+type reflectionServer struct {
+	grpc_reflection_v1.UnimplementedServerReflectionServer
+	services   *grpc.Server
+	extraFiles *protoregistry.Files
+}
+
+func (r *reflectionServer) ServerReflectionInfo(stream grpc_reflection_v1.ServerReflection_ServerReflectionInfoServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp := &grpc_reflection_v1.ServerReflectionResponse{
+			ValidHost:       req.GetHost(),
+			OriginalRequest: req,
+		}
+
+		switch mr := req.MessageRequest.(type) {
+		case *grpc_reflection_v1.ServerReflectionRequest_ListServices:
+			resp.MessageResponse = &grpc_reflection_v1.ServerReflectionResponse_ListServicesResponse{
+				ListServicesResponse: &grpc_reflection_v1.ListServiceResponse{
+					Service: listServices(r.services),
+				},
+			}
+		case *grpc_reflection_v1.ServerReflectionRequest_FileByFilename:
+			fds, err := fileByFilename(r.extraFiles, mr.FileByFilename)
+			if err != nil {
+				resp.MessageResponse = errorResponse(err)
+			} else {
+				resp.MessageResponse = fileDescriptorResponse(fds)
+			}
+		case *grpc_reflection_v1.ServerReflectionRequest_FileContainingSymbol:
+			fds, err := fileContainingSymbol(r.extraFiles, mr.FileContainingSymbol)
+			if err != nil {
+				resp.MessageResponse = errorResponse(err)
+			} else {
+				resp.MessageResponse = fileDescriptorResponse(fds)
+			}
+		case *grpc_reflection_v1.ServerReflectionRequest_FileContainingExtension:
+			ext := mr.FileContainingExtension
+			fds, err := fileContainingExtension(r.extraFiles, ext.GetContainingType(), ext.GetExtensionNumber())
+			if err != nil {
+				resp.MessageResponse = errorResponse(err)
+			} else {
+				resp.MessageResponse = fileDescriptorResponse(fds)
+			}
+		case *grpc_reflection_v1.ServerReflectionRequest_AllExtensionNumbersOfType:
+			nums, err := allExtensionNumbersOfType(r.extraFiles, mr.AllExtensionNumbersOfType)
+			if err != nil {
+				resp.MessageResponse = errorResponse(err)
+			} else {
+				resp.MessageResponse = &grpc_reflection_v1.ServerReflectionResponse_AllExtensionNumbersResponse{
+					AllExtensionNumbersResponse: &grpc_reflection_v1.ExtensionNumberResponse{
+						BaseTypeName:    mr.AllExtensionNumbersOfType,
+						ExtensionNumber: nums,
+					},
+				}
+			}
+		default:
+			resp.MessageResponse = errorResponse(fmt.Errorf("unsupported reflection request %T", mr))
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func fileDescriptorResponse(fds []*descriptorpb.FileDescriptorProto) *grpc_reflection_v1.ServerReflectionResponse_FileDescriptorResponse {
+	raw := make([][]byte, 0, len(fds))
+	for _, fd := range fds {
+		b, err := proto.Marshal(fd)
+		if err != nil {
+			continue
+		}
+		raw = append(raw, b)
+	}
+	return &grpc_reflection_v1.ServerReflectionResponse_FileDescriptorResponse{
+		FileDescriptorResponse: &grpc_reflection_v1.FileDescriptorResponse{FileDescriptorProto: raw},
+	}
+}
+
+func errorResponse(err error) *grpc_reflection_v1.ServerReflectionResponse_ErrorResponse {
+	return &grpc_reflection_v1.ServerReflectionResponse_ErrorResponse{
+		ErrorResponse: &grpc_reflection_v1.ErrorResponse{
+			ErrorCode:    int32(codes.NotFound),
+			ErrorMessage: err.Error(),
+		},
+	}
+}
+
+func listServices(s *grpc.Server) []*grpc_reflection_v1.ServiceResponse {
+	info := s.GetServiceInfo()
+	out := make([]*grpc_reflection_v1.ServiceResponse, 0, len(info))
+	for name := range info {
+		out = append(out, &grpc_reflection_v1.ServiceResponse{Name: name})
+	}
+	return out
+}
+
+// fileByFilename looks up a single file descriptor by its proto path,
+// consulting extra (from WithProtoSources) before protoregistry.GlobalFiles.
+func fileByFilename(extra *protoregistry.Files, name string) ([]*descriptorpb.FileDescriptorProto, error) {
+	fd, err := findFileByPath(extra, name)
+	if err != nil {
+		return nil, fmt.Errorf("file not found: %s: %w", name, err)
+	}
+	return []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(fd)}, nil
+}
+
+// fileContainingSymbol finds the file that declares the given fully
+// qualified symbol (service, method, message, enum, or field).
+func fileContainingSymbol(extra *protoregistry.Files, symbol string) ([]*descriptorpb.FileDescriptorProto, error) {
+	d, err := findDescriptorByName(extra, protoreflect.FullName(symbol))
+	if err != nil {
+		return nil, fmt.Errorf("symbol not found: %s: %w", symbol, err)
+	}
+	return []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(d.ParentFile())}, nil
+}
+
+// fileContainingExtension finds the file that declares an extension of
+// containingType at the given field number.
+func fileContainingExtension(extra *protoregistry.Files, containingType string, extensionNumber int32) ([]*descriptorpb.FileDescriptorProto, error) {
+	var found protoreflect.ExtensionDescriptor
+	rangeFiles(extra, func(fd protoreflect.FileDescriptor) bool {
+		exts := fd.Extensions()
+		for i := 0; i < exts.Len(); i++ {
+			ext := exts.Get(i)
+			if string(ext.ContainingMessage().FullName()) == containingType && int32(ext.Number()) == extensionNumber {
+				found = ext
+				return false
+			}
+		}
+		return true
+	})
+	if found == nil {
+		return nil, fmt.Errorf("extension %d of %s not found", extensionNumber, containingType)
+	}
+	return []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(found.ParentFile())}, nil
+}
+
+// allExtensionNumbersOfType returns every registered extension number for
+// the given message type.
+func allExtensionNumbersOfType(extra *protoregistry.Files, containingType string) ([]int32, error) {
+	msgName := protoreflect.FullName(containingType)
+	if _, err := findDescriptorByName(extra, msgName); err != nil {
+		return nil, fmt.Errorf("type not found: %s: %w", containingType, err)
+	}
+
+	var nums []int32
+	rangeFiles(extra, func(fd protoreflect.FileDescriptor) bool {
+		exts := fd.Extensions()
+		for i := 0; i < exts.Len(); i++ {
+			ext := exts.Get(i)
+			if ext.ContainingMessage().FullName() == msgName {
+				nums = append(nums, int32(ext.Number()))
+			}
+		}
+		return true
+	})
+	return nums, nil
+}
+
+// v1alphaReflectionServer adapts reflectionServer to the older v1alpha
+// wire format, which is structurally identical but uses distinct
+// generated types. Most gRPC tooling (grpcurl, evans, grpcui) still
+// probes v1alpha before falling back to v1.
+type v1alphaReflectionServer struct {
+	*reflectionServer
+}
+
+func (r v1alphaReflectionServer) ServerReflectionInfo(stream grpc_reflection_v1alpha.ServerReflection_ServerReflectionInfoServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp := &grpc_reflection_v1alpha.ServerReflectionResponse{
+			ValidHost:       req.GetHost(),
+			OriginalRequest: req,
+		}
+
+		switch mr := req.MessageRequest.(type) {
+		case *grpc_reflection_v1alpha.ServerReflectionRequest_ListServices:
+			info := r.services.GetServiceInfo()
+			services := make([]*grpc_reflection_v1alpha.ServiceResponse, 0, len(info))
+			for name := range info {
+				services = append(services, &grpc_reflection_v1alpha.ServiceResponse{Name: name})
+			}
+			resp.MessageResponse = &grpc_reflection_v1alpha.ServerReflectionResponse_ListServicesResponse{
+				ListServicesResponse: &grpc_reflection_v1alpha.ListServiceResponse{Service: services},
+			}
+		case *grpc_reflection_v1alpha.ServerReflectionRequest_FileByFilename:
+			fds, err := fileByFilename(r.extraFiles, mr.FileByFilename)
+			if err != nil {
+				resp.MessageResponse = v1alphaErrorResponse(err)
+			} else {
+				resp.MessageResponse = v1alphaFileDescriptorResponse(fds)
+			}
+		case *grpc_reflection_v1alpha.ServerReflectionRequest_FileContainingSymbol:
+			fds, err := fileContainingSymbol(r.extraFiles, mr.FileContainingSymbol)
+			if err != nil {
+				resp.MessageResponse = v1alphaErrorResponse(err)
+			} else {
+				resp.MessageResponse = v1alphaFileDescriptorResponse(fds)
+			}
+		case *grpc_reflection_v1alpha.ServerReflectionRequest_FileContainingExtension:
+			ext := mr.FileContainingExtension
+			fds, err := fileContainingExtension(r.extraFiles, ext.GetContainingType(), ext.GetExtensionNumber())
+			if err != nil {
+				resp.MessageResponse = v1alphaErrorResponse(err)
+			} else {
+				resp.MessageResponse = v1alphaFileDescriptorResponse(fds)
+			}
+		case *grpc_reflection_v1alpha.ServerReflectionRequest_AllExtensionNumbersOfType:
+			nums, err := allExtensionNumbersOfType(r.extraFiles, mr.AllExtensionNumbersOfType)
+			if err != nil {
+				resp.MessageResponse = v1alphaErrorResponse(err)
+			} else {
+				resp.MessageResponse = &grpc_reflection_v1alpha.ServerReflectionResponse_AllExtensionNumbersResponse{
+					AllExtensionNumbersResponse: &grpc_reflection_v1alpha.ExtensionNumberResponse{
+						BaseTypeName:    mr.AllExtensionNumbersOfType,
+						ExtensionNumber: nums,
+					},
+				}
+			}
+		default:
+			resp.MessageResponse = v1alphaErrorResponse(fmt.Errorf("unsupported reflection request %T", mr))
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func v1alphaFileDescriptorResponse(fds []*descriptorpb.FileDescriptorProto) *grpc_reflection_v1alpha.ServerReflectionResponse_FileDescriptorResponse {
+	raw := make([][]byte, 0, len(fds))
+	for _, fd := range fds {
+		b, err := proto.Marshal(fd)
+		if err != nil {
+			continue
+		}
+		raw = append(raw, b)
+	}
+	return &grpc_reflection_v1alpha.ServerReflectionResponse_FileDescriptorResponse{
+		FileDescriptorResponse: &grpc_reflection_v1alpha.FileDescriptorResponse{FileDescriptorProto: raw},
+	}
+}
+
+func v1alphaErrorResponse(err error) *grpc_reflection_v1alpha.ServerReflectionResponse_ErrorResponse {
+	return &grpc_reflection_v1alpha.ServerReflectionResponse_ErrorResponse{
+		ErrorResponse: &grpc_reflection_v1alpha.ErrorResponse{
+			ErrorCode:    int32(codes.NotFound),
+			ErrorMessage: err.Error(),
+		},
+	}
+}
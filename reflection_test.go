@@ -0,0 +1,162 @@
+package reflector
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// registerReflectionTestService builds, registers with protoregistry.GlobalFiles,
+// and mounts on s a single-method "reflectionpkg.Greeter" service, so
+// ServerReflectionInfo has a real service+file to answer questions about.
+// The handler is never invoked -- only the descriptor and *grpc.Server's
+// own service-info table matter for reflection.
+func registerReflectionTestService(t *testing.T, s *grpc.Server) {
+	t.Helper()
+
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	tString := descriptorpb.FieldDescriptorProto_TYPE_STRING
+
+	fdProto := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("reflector_reflection_test.proto"),
+		Package: proto.String("reflectionpkg"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("HelloRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("name"), Number: proto.Int32(1), Label: &optional, Type: &tString},
+				},
+			},
+			{
+				Name: proto.String("HelloResponse"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("greeting"), Number: proto.Int32(1), Label: &optional, Type: &tString},
+				},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("Greeter"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{
+						Name:       proto.String("SayHello"),
+						InputType:  proto.String(".reflectionpkg.HelloRequest"),
+						OutputType: proto.String(".reflectionpkg.HelloResponse"),
+					},
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdProto, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	if err := protoregistry.GlobalFiles.RegisterFile(fd); err != nil {
+		t.Fatalf("RegisterFile: %v", err)
+	}
+
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "reflectionpkg.Greeter",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "SayHello"},
+		},
+		Metadata: "reflector_reflection_test.proto",
+	}, struct{}{})
+}
+
+// fakeReflectionStream implements
+// grpc_reflection_v1.ServerReflection_ServerReflectionInfoServer over an
+// in-memory request queue, so ServerReflectionInfo can be driven directly
+// without a real network connection.
+type fakeReflectionStream struct {
+	grpc.ServerStream
+	reqs      []*grpc_reflection_v1.ServerReflectionRequest
+	responses []*grpc_reflection_v1.ServerReflectionResponse
+}
+
+func (f *fakeReflectionStream) Send(resp *grpc_reflection_v1.ServerReflectionResponse) error {
+	f.responses = append(f.responses, resp)
+	return nil
+}
+
+func (f *fakeReflectionStream) Recv() (*grpc_reflection_v1.ServerReflectionRequest, error) {
+	if len(f.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := f.reqs[0]
+	f.reqs = f.reqs[1:]
+	return req, nil
+}
+
+func (f *fakeReflectionStream) Context() context.Context { return context.Background() }
+
+func TestServerReflectionInfo(t *testing.T) {
+	s := grpc.NewServer()
+	registerReflectionTestService(t, s)
+
+	impl := &reflectionServer{services: s}
+	stream := &fakeReflectionStream{
+		reqs: []*grpc_reflection_v1.ServerReflectionRequest{
+			{MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{ListServices: "*"}},
+			{MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_FileByFilename{FileByFilename: "reflector_reflection_test.proto"}},
+			{MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: "reflectionpkg.Greeter"}},
+		},
+	}
+
+	if err := impl.ServerReflectionInfo(stream); err != nil {
+		t.Fatalf("ServerReflectionInfo: %v", err)
+	}
+	if len(stream.responses) != 3 {
+		t.Fatalf("got %d responses, want 3", len(stream.responses))
+	}
+
+	listResp, ok := stream.responses[0].MessageResponse.(*grpc_reflection_v1.ServerReflectionResponse_ListServicesResponse)
+	if !ok {
+		t.Fatalf("responses[0] = %T, want ListServicesResponse", stream.responses[0].MessageResponse)
+	}
+	if got := listResp.ListServicesResponse.GetService(); len(got) != 1 || got[0].GetName() != "reflectionpkg.Greeter" {
+		t.Fatalf("ListServices = %v, want [reflectionpkg.Greeter]", got)
+	}
+
+	byFilename, ok := stream.responses[1].MessageResponse.(*grpc_reflection_v1.ServerReflectionResponse_FileDescriptorResponse)
+	if !ok {
+		t.Fatalf("responses[1] = %T, want FileDescriptorResponse", stream.responses[1].MessageResponse)
+	}
+	raw := byFilename.FileDescriptorResponse.GetFileDescriptorProto()
+	if len(raw) != 1 {
+		t.Fatalf("FileByFilename returned %d descriptors, want 1", len(raw))
+	}
+	var gotFD descriptorpb.FileDescriptorProto
+	if err := proto.Unmarshal(raw[0], &gotFD); err != nil {
+		t.Fatalf("unmarshal file descriptor: %v", err)
+	}
+	if gotFD.GetName() != "reflector_reflection_test.proto" {
+		t.Fatalf("FileByFilename name = %q, want reflector_reflection_test.proto", gotFD.GetName())
+	}
+
+	bySymbol, ok := stream.responses[2].MessageResponse.(*grpc_reflection_v1.ServerReflectionResponse_FileDescriptorResponse)
+	if !ok {
+		t.Fatalf("responses[2] = %T, want FileDescriptorResponse", stream.responses[2].MessageResponse)
+	}
+	raw = bySymbol.FileDescriptorResponse.GetFileDescriptorProto()
+	if len(raw) != 1 {
+		t.Fatalf("FileContainingSymbol returned %d descriptors, want 1", len(raw))
+	}
+	gotFD = descriptorpb.FileDescriptorProto{}
+	if err := proto.Unmarshal(raw[0], &gotFD); err != nil {
+		t.Fatalf("unmarshal file descriptor: %v", err)
+	}
+	if gotFD.GetPackage() != "reflectionpkg" {
+		t.Fatalf("FileContainingSymbol package = %q, want reflectionpkg", gotFD.GetPackage())
+	}
+}
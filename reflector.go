@@ -11,23 +11,27 @@ import (
 
 // ServiceInfo holds information about a gRPC service
 type ServiceInfo struct {
-	Name    string
-	Methods []MethodInfo
+	Name     string
+	Methods  []MethodInfo
+	Comments Comments
 }
 
 // MethodInfo holds information about a gRPC method
 type MethodInfo struct {
-	Name       string
-	InputType  string
-	OutputType string
+	Name         string
+	InputType    string
+	OutputType   string
 	InputSchema  *MessageSchema
 	OutputSchema *MessageSchema
+	Comments     Comments
 }
 
 // MessageSchema holds the schema of a protobuf message
 type MessageSchema struct {
-	Name   string
-	Fields []FieldInfo
+	Name     string
+	Fields   []FieldInfo
+	Oneofs   []OneofInfo
+	Comments Comments
 }
 
 // FieldInfo holds information about a protobuf message field
@@ -36,10 +40,49 @@ type FieldInfo struct {
 	Number   int32
 	Type     string
 	Repeated bool
+	Comments Comments
+
+	// Message is populated when the field is a (non-map) message type,
+	// with the nested schema of that message.
+	Message *MessageSchema
+	// Enum is populated when the field is an enum type.
+	Enum *EnumSchema
+	// Map is populated when field.IsMap() is true. Type is then "map",
+	// Message and Enum are nil, and the key and value are described by
+	// Map.Key and Map.Value.
+	Map *MapEntry
+}
+
+// EnumSchema holds the name and allowed values of a protobuf enum.
+type EnumSchema struct {
+	Name   string
+	Values []EnumValueInfo
+}
+
+// EnumValueInfo holds a single named value of an enum.
+type EnumValueInfo struct {
+	Name   string
+	Number int32
+}
+
+// MapEntry describes the key and value of a map<key, value> field.
+type MapEntry struct {
+	Key   FieldInfo
+	Value FieldInfo
+}
+
+// OneofInfo holds the set of field names grouped under a oneof.
+type OneofInfo struct {
+	Name   string
+	Fields []string
 }
 
 // GetServices extracts all registered services and their methods from a gRPC server
-func GetServices(server *grpc.Server) ([]ServiceInfo, error) {
+func GetServices(server *grpc.Server, opts ...Option) ([]ServiceInfo, error) {
+	options, err := resolveOptions(opts)
+	if err != nil {
+		return nil, err
+	}
 	var services []ServiceInfo
 
 	// Get all registered services via reflection
@@ -47,8 +90,9 @@ func GetServices(server *grpc.Server) ([]ServiceInfo, error) {
 
 	for serviceName, info := range serviceInfo {
 		service := ServiceInfo{
-			Name:    serviceName,
-			Methods: []MethodInfo{},
+			Name:     serviceName,
+			Methods:  []MethodInfo{},
+			Comments: lookupComments(options.SourceInfo, protoreflect.FullName(serviceName)),
 		}
 
 		// Get method information
@@ -59,17 +103,18 @@ func GetServices(server *grpc.Server) ([]ServiceInfo, error) {
 
 			// Try to get full method descriptor to extract input/output types
 			fullMethodName := fmt.Sprintf("/%s/%s", serviceName, method.Name)
-			if desc, err := getMethodDescriptor(fullMethodName); err == nil {
+			if desc, err := getMethodDescriptor(fullMethodName, options.ProtoFiles); err == nil {
 				methodInfo.InputType = string(desc.Input().FullName())
 				methodInfo.OutputType = string(desc.Output().FullName())
+				methodInfo.Comments = lookupComments(options.SourceInfo, desc.FullName())
 
 				// Get input schema
-				if inputSchema, err := getMessageSchema(desc.Input()); err == nil {
+				if inputSchema, err := getMessageSchema(desc.Input(), options, map[protoreflect.FullName]bool{}, 0); err == nil {
 					methodInfo.InputSchema = inputSchema
 				}
 
 				// Get output schema
-				if outputSchema, err := getMessageSchema(desc.Output()); err == nil {
+				if outputSchema, err := getMessageSchema(desc.Output(), options, map[protoreflect.FullName]bool{}, 0); err == nil {
 					methodInfo.OutputSchema = outputSchema
 				}
 			}
@@ -83,8 +128,25 @@ func GetServices(server *grpc.Server) ([]ServiceInfo, error) {
 	return services, nil
 }
 
-// getMethodDescriptor attempts to find a method descriptor by full method name
-func getMethodDescriptor(fullMethodName string) (protoreflect.MethodDescriptor, error) {
+// MethodDescriptor exports getMethodDescriptor's lookup for subpackages
+// (e.g. gateway) and other callers that need the raw protoreflect
+// descriptor rather than the flattened MethodInfo/MessageSchema views.
+// WithProtoSources is honored if passed, so descriptors of services
+// whose generated Go stubs aren't linked into the binary can still be
+// resolved.
+func MethodDescriptor(fullMethodName string, opts ...Option) (protoreflect.MethodDescriptor, error) {
+	options, err := resolveOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return getMethodDescriptor(fullMethodName, options.ProtoFiles)
+}
+
+// getMethodDescriptor attempts to find a method descriptor by full method
+// name, consulting extra (typically Options.ProtoFiles, from
+// WithProtoSources) before falling back to protoregistry.GlobalFiles.
+// extra may be nil.
+func getMethodDescriptor(fullMethodName string, extra *protoregistry.Files) (protoreflect.MethodDescriptor, error) {
 	// Parse full method name: /package.service/method
 	parts := strings.Split(strings.TrimPrefix(fullMethodName, "/"), "/")
 	if len(parts) != 2 {
@@ -93,9 +155,11 @@ func getMethodDescriptor(fullMethodName string) (protoreflect.MethodDescriptor,
 
 	serviceName := parts[0]
 
-	// Try to find the service descriptor in the global registry
+	// Try to find the service descriptor, searching extra first so
+	// locally compiled sources can shadow (or supply entirely) services
+	// the global registry doesn't know about.
 	var methodDesc protoreflect.MethodDescriptor
-	protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+	findMethod := func(fd protoreflect.FileDescriptor) bool {
 		services := fd.Services()
 		for i := 0; i < services.Len(); i++ {
 			sd := services.Get(i)
@@ -111,7 +175,14 @@ func getMethodDescriptor(fullMethodName string) (protoreflect.MethodDescriptor,
 			}
 		}
 		return true // Continue iteration
-	})
+	}
+
+	if extra != nil {
+		extra.RangeFiles(findMethod)
+	}
+	if methodDesc == nil {
+		protoregistry.GlobalFiles.RangeFiles(findMethod)
+	}
 
 	if methodDesc == nil {
 		return nil, fmt.Errorf("method descriptor not found for %s", fullMethodName)
@@ -120,38 +191,136 @@ func getMethodDescriptor(fullMethodName string) (protoreflect.MethodDescriptor,
 	return methodDesc, nil
 }
 
-// getMessageSchema extracts the schema of a protobuf message
-func getMessageSchema(msgDesc protoreflect.MessageDescriptor) (*MessageSchema, error) {
+// getMessageSchema extracts the schema of a protobuf message, recursing
+// into nested message and map-value fields. visited tracks the message
+// full names already on the current recursion path so self-referential
+// protos (e.g. a tree node with a repeated field of its own type) don't
+// recurse forever; once a name is revisited, its schema is returned as a
+// name-only leaf. depth is compared against options.MaxDepth for the
+// same purpose.
+func getMessageSchema(msgDesc protoreflect.MessageDescriptor, options Options, visited map[protoreflect.FullName]bool, depth int) (*MessageSchema, error) {
 	if msgDesc == nil {
 		return nil, fmt.Errorf("message descriptor is nil")
 	}
 
 	schema := &MessageSchema{
-		Name:   string(msgDesc.FullName()),
-		Fields: []FieldInfo{},
+		Name:     string(msgDesc.FullName()),
+		Fields:   []FieldInfo{},
+		Comments: lookupComments(options.SourceInfo, msgDesc.FullName()),
+	}
+
+	if visited[msgDesc.FullName()] || (options.MaxDepth > 0 && depth >= options.MaxDepth) {
+		return schema, nil
 	}
+	visited[msgDesc.FullName()] = true
+	defer delete(visited, msgDesc.FullName())
 
 	fields := msgDesc.Fields()
 	for i := 0; i < fields.Len(); i++ {
 		field := fields.Get(i)
-		fieldInfo := FieldInfo{
-			Name:     string(field.Name()),
-			Number:   int32(field.Number()),
-			Type:     field.Kind().String(),
-			Repeated: field.Cardinality() == protoreflect.Repeated,
-		}
 
-		// For message types, use the full type name
-		if field.Kind() == protoreflect.MessageKind {
-			fieldInfo.Type = string(field.Message().FullName())
+		if field.IsMap() {
+			mapEntry, err := mapEntryInfo(field, options, visited, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			schema.Fields = append(schema.Fields, FieldInfo{
+				Name:     string(field.Name()),
+				Number:   int32(field.Number()),
+				Type:     "map",
+				Map:      mapEntry,
+				Comments: lookupComments(options.SourceInfo, field.FullName()),
+			})
+			continue
 		}
 
+		fieldInfo, err := fieldInfo(field, options, visited, depth+1)
+		if err != nil {
+			return nil, err
+		}
 		schema.Fields = append(schema.Fields, fieldInfo)
 	}
 
+	oneofs := msgDesc.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		oneof := oneofs.Get(i)
+		if oneof.IsSynthetic() {
+			// Synthetic oneofs back proto3 optional fields, not
+			// user-declared oneofs; they add no useful grouping.
+			continue
+		}
+		oneofFields := oneof.Fields()
+		names := make([]string, 0, oneofFields.Len())
+		for j := 0; j < oneofFields.Len(); j++ {
+			names = append(names, string(oneofFields.Get(j).Name()))
+		}
+		schema.Oneofs = append(schema.Oneofs, OneofInfo{
+			Name:   string(oneof.Name()),
+			Fields: names,
+		})
+	}
+
 	return schema, nil
 }
 
+// fieldInfo builds the FieldInfo for a non-map field, recursing into
+// message and enum fields.
+func fieldInfo(field protoreflect.FieldDescriptor, options Options, visited map[protoreflect.FullName]bool, depth int) (FieldInfo, error) {
+	info := FieldInfo{
+		Name:     string(field.Name()),
+		Number:   int32(field.Number()),
+		Type:     field.Kind().String(),
+		Repeated: field.Cardinality() == protoreflect.Repeated,
+		Comments: lookupComments(options.SourceInfo, field.FullName()),
+	}
+
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		info.Type = string(field.Message().FullName())
+		nested, err := getMessageSchema(field.Message(), options, visited, depth)
+		if err != nil {
+			return FieldInfo{}, err
+		}
+		info.Message = nested
+	case protoreflect.EnumKind:
+		info.Type = string(field.Enum().FullName())
+		info.Enum = enumSchema(field.Enum())
+	}
+
+	return info, nil
+}
+
+// mapEntryInfo builds the MapEntry for a map<key, value> field.
+func mapEntryInfo(field protoreflect.FieldDescriptor, options Options, visited map[protoreflect.FullName]bool, depth int) (*MapEntry, error) {
+	entry := field.Message()
+	keyInfo, err := fieldInfo(entry.Fields().ByName("key"), options, visited, depth)
+	if err != nil {
+		return nil, err
+	}
+	valueInfo, err := fieldInfo(entry.Fields().ByName("value"), options, visited, depth)
+	if err != nil {
+		return nil, err
+	}
+	return &MapEntry{Key: keyInfo, Value: valueInfo}, nil
+}
+
+// enumSchema extracts the name and values of an enum descriptor.
+func enumSchema(enumDesc protoreflect.EnumDescriptor) *EnumSchema {
+	values := enumDesc.Values()
+	schema := &EnumSchema{
+		Name:   string(enumDesc.FullName()),
+		Values: make([]EnumValueInfo, 0, values.Len()),
+	}
+	for i := 0; i < values.Len(); i++ {
+		v := values.Get(i)
+		schema.Values = append(schema.Values, EnumValueInfo{
+			Name:   string(v.Name()),
+			Number: int32(v.Number()),
+		})
+	}
+	return schema
+}
+
 // FormatServices formats service information for logging or display
 func FormatServices(services []ServiceInfo) string {
 	var sb strings.Builder
@@ -175,32 +344,97 @@ func FormatServicesDetailed(services []ServiceInfo) string {
 	var sb strings.Builder
 
 	for _, service := range services {
-		sb.WriteString(fmt.Sprintf("Service: %s\n", service.Name))
+		writeComments(&sb, service.Comments, "")
+		sb.WriteString(fmt.Sprintf("Service: %s%s\n", service.Name, trailingSuffix(service.Comments)))
 		for _, method := range service.Methods {
-			sb.WriteString(fmt.Sprintf("  Method: %s\n", method.Name))
+			writeComments(&sb, method.Comments, "  ")
+			sb.WriteString(fmt.Sprintf("  Method: %s%s\n", method.Name, trailingSuffix(method.Comments)))
 			sb.WriteString(fmt.Sprintf("    Input:  %s\n", method.InputType))
-			if method.InputSchema != nil {
-				for _, field := range method.InputSchema.Fields {
-					repeated := ""
-					if field.Repeated {
-						repeated = "repeated "
-					}
-					sb.WriteString(fmt.Sprintf("      - %s%s %s = %d\n", repeated, field.Type, field.Name, field.Number))
-				}
-			}
+			writeMessageSchema(&sb, method.InputSchema, "      ")
 			sb.WriteString(fmt.Sprintf("    Output: %s\n", method.OutputType))
-			if method.OutputSchema != nil {
-				for _, field := range method.OutputSchema.Fields {
-					repeated := ""
-					if field.Repeated {
-						repeated = "repeated "
-					}
-					sb.WriteString(fmt.Sprintf("      - %s%s %s = %d\n", repeated, field.Type, field.Name, field.Number))
-				}
-			}
+			writeMessageSchema(&sb, method.OutputSchema, "      ")
 			sb.WriteString("\n")
 		}
 	}
 
 	return sb.String()
 }
+
+// writeComments renders any non-empty Comments as "// "-prefixed lines
+// at indent, detached paragraphs first, matching how protoc-generated
+// doc comments are laid out.
+func writeComments(sb *strings.Builder, c Comments, indent string) {
+	if c.IsEmpty() {
+		return
+	}
+	for _, detached := range c.LeadingDetached {
+		for _, line := range strings.Split(strings.TrimRight(detached, "\n"), "\n") {
+			sb.WriteString(fmt.Sprintf("%s// %s\n", indent, line))
+		}
+		sb.WriteString("\n")
+	}
+	for _, line := range strings.Split(strings.TrimRight(c.Leading, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s// %s\n", indent, line))
+	}
+}
+
+// trailingSuffix renders c.Trailing as a "  // ..." suffix for appending to
+// the end of a declaration line, the way protoc attaches a comment on the
+// line after a field to that field rather than to whatever follows it --
+// exactly the style grpc-gateway annotations and single-line field docs
+// tend to use. Multi-line trailing comments are folded onto one line since
+// there's no following declaration to split them across.
+func trailingSuffix(c Comments) string {
+	trailing := strings.TrimSpace(strings.ReplaceAll(c.Trailing, "\n", " "))
+	if trailing == "" {
+		return ""
+	}
+	return "  // " + trailing
+}
+
+// writeMessageSchema recursively renders a MessageSchema's fields,
+// oneofs, maps, enums and nested messages under indent.
+func writeMessageSchema(sb *strings.Builder, schema *MessageSchema, indent string) {
+	if schema == nil {
+		return
+	}
+
+	for _, field := range schema.Fields {
+		repeated := ""
+		if field.Repeated {
+			repeated = "repeated "
+		}
+		writeComments(sb, field.Comments, indent)
+		trailing := trailingSuffix(field.Comments)
+
+		switch {
+		case field.Map != nil:
+			sb.WriteString(fmt.Sprintf("%s- map<%s, %s> %s = %d%s\n", indent, field.Map.Key.Type, field.Map.Value.Type, field.Name, field.Number, trailing))
+			if field.Map.Value.Message != nil {
+				writeMessageSchema(sb, field.Map.Value.Message, indent+"  ")
+			}
+		case field.Message != nil:
+			sb.WriteString(fmt.Sprintf("%s- %s%s %s = %d%s\n", indent, repeated, field.Type, field.Name, field.Number, trailing))
+			writeMessageSchema(sb, field.Message, indent+"  ")
+		case field.Enum != nil:
+			sb.WriteString(fmt.Sprintf("%s- %s%s %s = %d (%s)%s\n", indent, repeated, field.Type, field.Name, field.Number, strings.Join(enumValueNames(field.Enum), "|"), trailing))
+		default:
+			sb.WriteString(fmt.Sprintf("%s- %s%s %s = %d%s\n", indent, repeated, field.Type, field.Name, field.Number, trailing))
+		}
+	}
+
+	for _, oneof := range schema.Oneofs {
+		sb.WriteString(fmt.Sprintf("%soneof %s { %s }\n", indent, oneof.Name, strings.Join(oneof.Fields, ", ")))
+	}
+}
+
+func enumValueNames(enum *EnumSchema) []string {
+	names := make([]string, 0, len(enum.Values))
+	for _, v := range enum.Values {
+		names = append(names, v.Name)
+	}
+	return names
+}
@@ -0,0 +1,193 @@
+package reflector
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// buildSchemaWalkTestFile assembles, by hand, the FileDescriptorProto a
+// protoc run over the following would produce:
+//
+//	syntax = "proto3";
+//	package testpkg;
+//
+//	message Node { repeated Node children = 1; }
+//
+//	message Item { string name = 1; }
+//	message Container { map<string, Item> items = 1; }
+//
+//	message ChainA { ChainB b = 1; }
+//	message ChainB { ChainC c = 1; }
+//	message ChainC { string leaf = 1; }
+//
+// built directly so these tests don't depend on a protoc/protoc-gen-go
+// toolchain being available. It covers the three shapes getMessageSchema
+// has to guard against: a self-referential message, a map whose value is
+// itself a message, and a chain deep enough to probe MaxDepth.
+func buildSchemaWalkTestFile(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	tString := descriptorpb.FieldDescriptorProto_TYPE_STRING
+	tMessage := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+
+	fdProto := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("reflector_schema_walk_test.proto"),
+		Package: proto.String("testpkg"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Node"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("children"), Number: proto.Int32(1), Label: &repeated, Type: &tMessage, TypeName: proto.String(".testpkg.Node")},
+				},
+			},
+			{
+				Name: proto.String("Item"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("name"), Number: proto.Int32(1), Label: &optional, Type: &tString},
+				},
+			},
+			{
+				Name: proto.String("Container"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("items"), Number: proto.Int32(1), Label: &repeated, Type: &tMessage, TypeName: proto.String(".testpkg.Container.ItemsEntry")},
+				},
+				NestedType: []*descriptorpb.DescriptorProto{
+					{
+						Name:    proto.String("ItemsEntry"),
+						Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+						Field: []*descriptorpb.FieldDescriptorProto{
+							{Name: proto.String("key"), Number: proto.Int32(1), Label: &optional, Type: &tString},
+							{Name: proto.String("value"), Number: proto.Int32(2), Label: &optional, Type: &tMessage, TypeName: proto.String(".testpkg.Item")},
+						},
+					},
+				},
+			},
+			{
+				Name: proto.String("ChainA"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("b"), Number: proto.Int32(1), Label: &optional, Type: &tMessage, TypeName: proto.String(".testpkg.ChainB")},
+				},
+			},
+			{
+				Name: proto.String("ChainB"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("c"), Number: proto.Int32(1), Label: &optional, Type: &tMessage, TypeName: proto.String(".testpkg.ChainC")},
+				},
+			},
+			{
+				Name: proto.String("ChainC"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("leaf"), Number: proto.Int32(1), Label: &optional, Type: &tString},
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdProto, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	return fd
+}
+
+func messageDesc(t *testing.T, fd protoreflect.FileDescriptor, name protoreflect.Name) protoreflect.MessageDescriptor {
+	t.Helper()
+	md := fd.Messages().ByName(name)
+	if md == nil {
+		t.Fatalf("message %q not found in test file", name)
+	}
+	return md
+}
+
+// TestGetMessageSchemaSelfReferential ensures a message that refers to
+// itself (e.g. a tree node with a repeated field of its own type) expands
+// one level and then stops, rather than recursing forever.
+func TestGetMessageSchemaSelfReferential(t *testing.T) {
+	fd := buildSchemaWalkTestFile(t)
+	node := messageDesc(t, fd, "Node")
+
+	schema, err := getMessageSchema(node, Options{}, map[protoreflect.FullName]bool{}, 0)
+	if err != nil {
+		t.Fatalf("getMessageSchema: %v", err)
+	}
+
+	if len(schema.Fields) != 1 {
+		t.Fatalf("len(Fields) = %d, want 1", len(schema.Fields))
+	}
+	children := schema.Fields[0]
+	if children.Message == nil {
+		t.Fatalf("children.Message is nil, want nested Node schema")
+	}
+	if children.Message.Name != "testpkg.Node" {
+		t.Fatalf("children.Message.Name = %q, want testpkg.Node", children.Message.Name)
+	}
+	if len(children.Message.Fields) != 0 {
+		t.Fatalf("children.Message.Fields = %v, want empty (cycle should stop expansion)", children.Message.Fields)
+	}
+}
+
+// TestGetMessageSchemaMapOfMessages ensures a map<string, Message> field
+// is reported via FieldInfo.Map with the value side's nested message
+// schema fully expanded, not collapsed to the synthetic *Entry wrapper.
+func TestGetMessageSchemaMapOfMessages(t *testing.T) {
+	fd := buildSchemaWalkTestFile(t)
+	container := messageDesc(t, fd, "Container")
+
+	schema, err := getMessageSchema(container, Options{}, map[protoreflect.FullName]bool{}, 0)
+	if err != nil {
+		t.Fatalf("getMessageSchema: %v", err)
+	}
+
+	if len(schema.Fields) != 1 {
+		t.Fatalf("len(Fields) = %d, want 1", len(schema.Fields))
+	}
+	items := schema.Fields[0]
+	if items.Map == nil {
+		t.Fatalf("items.Map is nil, want a MapEntry")
+	}
+	if items.Map.Value.Message == nil {
+		t.Fatalf("items.Map.Value.Message is nil, want Item schema")
+	}
+	if items.Map.Value.Message.Name != "testpkg.Item" {
+		t.Fatalf("items.Map.Value.Message.Name = %q, want testpkg.Item", items.Map.Value.Message.Name)
+	}
+	if len(items.Map.Value.Message.Fields) != 1 || items.Map.Value.Message.Fields[0].Name != "name" {
+		t.Fatalf("items.Map.Value.Message.Fields = %v, want [name]", items.Map.Value.Message.Fields)
+	}
+}
+
+// TestGetMessageSchemaMaxDepth ensures a MaxDepth of 1 expands the fields
+// of the top-level message and returns its nested messages as name-only
+// leaves, rather than either ignoring the cap or cutting off one level
+// too early.
+func TestGetMessageSchemaMaxDepth(t *testing.T) {
+	fd := buildSchemaWalkTestFile(t)
+	chainA := messageDesc(t, fd, "ChainA")
+
+	schema, err := getMessageSchema(chainA, Options{MaxDepth: 1}, map[protoreflect.FullName]bool{}, 0)
+	if err != nil {
+		t.Fatalf("getMessageSchema: %v", err)
+	}
+
+	if len(schema.Fields) != 1 {
+		t.Fatalf("len(Fields) = %d, want 1", len(schema.Fields))
+	}
+	b := schema.Fields[0]
+	if b.Message == nil {
+		t.Fatalf("b.Message is nil, want ChainB schema")
+	}
+	if b.Message.Name != "testpkg.ChainB" {
+		t.Fatalf("b.Message.Name = %q, want testpkg.ChainB", b.Message.Name)
+	}
+	if len(b.Message.Fields) != 0 {
+		t.Fatalf("b.Message.Fields = %v, want empty (MaxDepth should stop expansion before ChainC)", b.Message.Fields)
+	}
+}